@@ -0,0 +1,109 @@
+package normalizer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetNormalizedEventKeyReappliesCardinalityGuardOnCacheHit(t *testing.T) {
+	rn := mustNormalizer(t, &requestNormalizerConfig{
+		MaxEventKeys:    1,
+		MaxEventKeysTTL: 5 * time.Millisecond,
+		CacheSize:       10,
+	})
+
+	fooReq := mustRequest(t, "GET", "/foo")
+	barReq := mustRequest(t, "GET", "/bar")
+
+	if got := rn.getNormalizedEventKey(fooReq); got != "GET:/foo" {
+		t.Fatalf("getNormalizedEventKey(/foo) = %q, want %q", got, "GET:/foo")
+	}
+	if got := rn.getNormalizedEventKey(barReq); got != rn.overflowEventKey {
+		t.Fatalf("getNormalizedEventKey(/bar) = %q, want overflow key %q before eviction", got, rn.overflowEventKey)
+	}
+
+	// /bar is now cached under its raw, pre-guard key "GET:/bar" while the guard returned the
+	// overflow key. Once MaxEventKeysTTL lapses, a cache hit for /bar must re-run
+	// limitCardinality rather than replaying the earlier overflow result forever.
+	time.Sleep(10 * time.Millisecond)
+
+	if got := rn.getNormalizedEventKey(barReq); got != "GET:/bar" {
+		t.Fatalf("getNormalizedEventKey(/bar) = %q, want %q once the cardinality guard's slot has freed up", got, "GET:/bar")
+	}
+}
+
+func TestLimitCardinalityOverflowsBeyondMaxEventKeys(t *testing.T) {
+	rn := mustNormalizer(t, &requestNormalizerConfig{MaxEventKeys: 2})
+
+	if got := rn.limitCardinality("a"); got != "a" {
+		t.Fatalf("limitCardinality(a) = %q, want %q", got, "a")
+	}
+	if got := rn.limitCardinality("b"); got != "b" {
+		t.Fatalf("limitCardinality(b) = %q, want %q", got, "b")
+	}
+	if got := rn.limitCardinality("c"); got != rn.overflowEventKey {
+		t.Fatalf("limitCardinality(c) = %q, want overflow key %q", got, rn.overflowEventKey)
+	}
+	// A previously seen key still passes through even while at the limit.
+	if got := rn.limitCardinality("a"); got != "a" {
+		t.Fatalf("limitCardinality(a) = %q, want %q", got, "a")
+	}
+}
+
+func TestLimitCardinalityEvictsExpiredKeys(t *testing.T) {
+	rn := mustNormalizer(t, &requestNormalizerConfig{MaxEventKeys: 1, MaxEventKeysTTL: time.Millisecond})
+
+	if got := rn.limitCardinality("a"); got != "a" {
+		t.Fatalf("limitCardinality(a) = %q, want %q", got, "a")
+	}
+	if got := rn.limitCardinality("b"); got != rn.overflowEventKey {
+		t.Fatalf("limitCardinality(b) = %q, want overflow key %q before eviction", got, rn.overflowEventKey)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if got := rn.limitCardinality("b"); got != "b" {
+		t.Fatalf("limitCardinality(b) = %q, want %q once \"a\" has expired out of the window", got, "b")
+	}
+}
+
+func TestLimitCardinalityWithoutLimitIsNoop(t *testing.T) {
+	rn := mustNormalizer(t, &requestNormalizerConfig{})
+	for _, key := range []string{"a", "b", "c"} {
+		if got := rn.limitCardinality(key); got != key {
+			t.Fatalf("limitCardinality(%s) = %q, want unchanged %q when MaxEventKeys is unset", key, got, key)
+		}
+	}
+}
+
+func TestOverflowAndDistinctKeyMetricsAreScopedPerInstance(t *testing.T) {
+	counterA := newTestCounterVec()
+	gaugeA := newTestGauge()
+	rnA := mustNormalizer(t, &requestNormalizerConfig{MaxEventKeys: 1})
+	rnA.SetOverflowEventsCounter(counterA)
+	rnA.SetDistinctEventKeysGauge(gaugeA)
+
+	counterB := newTestCounterVec()
+	gaugeB := newTestGauge()
+	rnB := mustNormalizer(t, &requestNormalizerConfig{MaxEventKeys: 1})
+	rnB.SetOverflowEventsCounter(counterB)
+	rnB.SetDistinctEventKeysGauge(gaugeB)
+
+	rnA.limitCardinality("a")
+	rnA.limitCardinality("overflow-on-a")
+
+	if got := testCounterValue(counterA, "cardinality"); got != 1 {
+		t.Fatalf("instance A overflow counter = %v, want 1", got)
+	}
+	if got := testCounterValue(counterB, "cardinality"); got != 0 {
+		t.Fatalf("instance B overflow counter = %v, want 0 (instances must not share a metric)", got)
+	}
+
+	rnB.limitCardinality("b")
+	if got := testGaugeValue(gaugeB); got != 1 {
+		t.Fatalf("instance B distinct key gauge = %v, want 1", got)
+	}
+	if got := testGaugeValue(gaugeA); got != 1 {
+		t.Fatalf("instance A distinct key gauge = %v, want 1 (unaffected by instance B)", got)
+	}
+}
@@ -0,0 +1,157 @@
+// Package mapping implements statsd_exporter-style glob/template path mapping: matching a
+// request's method, path segments and query string against a declared Rule and rendering
+// an EventKey from the captures via a Go text/template.
+package mapping
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const pathItemsSeparator = "/"
+
+// placeholderRegex matches placeholders of the form ${1}, ${method}, ${query.foo} used in
+// a Rule's EventKey template.
+var placeholderRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// Rule matches a request against a statsd_exporter-style glob pattern over the path
+// segments (with "*" capturing a single segment and "**" capturing the rest of the path)
+// and, optionally, the request method and query string. On a match, EventKey is rendered
+// as a Go text/template where captured segments are referenced as ${1}, ${2}, ..., the
+// method as ${method} and a query parameter "foo" as ${query.foo}.
+type Rule struct {
+	Match    string
+	Method   string
+	Query    map[string]string
+	EventKey string
+
+	matchSegments []string
+	keyTemplate   *template.Template
+}
+
+// Compile precompiles the glob pattern and the EventKey template so that neither is
+// re-parsed on every request. It also validates that every placeholder
+// referenced by EventKey is actually reachable from Match/Query, failing fast on a rule
+// that would otherwise silently render blanks.
+func (r *Rule) Compile() error {
+	if r.Match == "" {
+		return fmt.Errorf("mapping rule Match must not be empty")
+	}
+	r.matchSegments = SplitPathSegments(r.Match)
+	for i, segment := range r.matchSegments {
+		if segment == "**" && i != len(r.matchSegments)-1 {
+			return fmt.Errorf("mapping rule %q: \"**\" is only allowed as the last path segment", r.Match)
+		}
+	}
+	if err := r.validatePlaceholders(); err != nil {
+		return err
+	}
+	rawTemplate := placeholderRegex.ReplaceAllString(r.EventKey, `{{index .Vars "$1"}}`)
+	keyTemplate, err := template.New("mappingRuleEventKey").Parse(rawTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse EventKey template %q: %w", r.EventKey, err)
+	}
+	r.keyTemplate = keyTemplate
+	return nil
+}
+
+// validatePlaceholders ensures every ${...} placeholder in EventKey names a capture the
+// rule can actually produce: a numbered capture up to the number of "*"/"**" segments in
+// Match, "method", or "query.<param>" for a param constrained by Query.
+func (r *Rule) validatePlaceholders() error {
+	captureCount := 0
+	for _, segment := range r.matchSegments {
+		if segment == "*" || segment == "**" {
+			captureCount++
+		}
+	}
+	valid := map[string]bool{"method": true}
+	for param := range r.Query {
+		valid["query."+param] = true
+	}
+	for i := 1; i <= captureCount; i++ {
+		valid[strconv.Itoa(i)] = true
+	}
+	for _, match := range placeholderRegex.FindAllStringSubmatch(r.EventKey, -1) {
+		if !valid[match[1]] {
+			return fmt.Errorf("mapping rule %q: EventKey references unknown placeholder ${%s}", r.Match, match[1])
+		}
+	}
+	return nil
+}
+
+// Evaluate reports whether the given method and path segments satisfy the rule, returning
+// the captured placeholder values on success.
+func (r *Rule) Evaluate(method string, pathSegments []string, query url.Values) (map[string]string, bool) {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return nil, false
+	}
+	captures := make(map[string]string)
+	captureIndex := 0
+	for i, segment := range r.matchSegments {
+		if segment == "**" {
+			if i >= len(pathSegments) {
+				return nil, false
+			}
+			captureIndex++
+			captures[strconv.Itoa(captureIndex)] = strings.Join(pathSegments[i:], pathItemsSeparator)
+			pathSegments = pathSegments[:i]
+			break
+		}
+		if i >= len(pathSegments) {
+			return nil, false
+		}
+		if segment == "*" {
+			captureIndex++
+			captures[strconv.Itoa(captureIndex)] = pathSegments[i]
+			continue
+		}
+		if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+	if len(r.matchSegments) == 0 || r.matchSegments[len(r.matchSegments)-1] != "**" {
+		if len(pathSegments) != len(r.matchSegments) {
+			return nil, false
+		}
+	}
+	for param, expected := range r.Query {
+		got := query.Get(param)
+		if expected == "*" {
+			if got == "" {
+				return nil, false
+			}
+		} else if got != expected {
+			return nil, false
+		}
+		captures["query."+param] = got
+	}
+	captures["method"] = method
+	return captures, true
+}
+
+// RenderEventKey executes the rule's EventKey template against the captured placeholder
+// values.
+func (r *Rule) RenderEventKey(captures map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.keyTemplate.Execute(&buf, struct{ Vars map[string]string }{Vars: captures}); err != nil {
+		return "", fmt.Errorf("failed to render EventKey template %q: %w", r.EventKey, err)
+	}
+	return buf.String(), nil
+}
+
+// SplitPathSegments splits a cleaned URL path into its non-empty segments, e.g.
+// "/api/v1/users/" becomes ["api", "v1", "users"].
+func SplitPathSegments(rawPath string) []string {
+	cleaned := strings.Trim(path.Clean(rawPath), pathItemsSeparator)
+	if cleaned == "" || cleaned == "." {
+		return nil
+	}
+	return strings.Split(cleaned, pathItemsSeparator)
+}
@@ -0,0 +1,120 @@
+package mapping
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestRuleCompileValidatesPlaceholders(t *testing.T) {
+	r := &Rule{Match: "/api/*", EventKey: "api_${1}_${5}"}
+	if err := r.Compile(); err == nil {
+		t.Fatal("expected Compile to reject a placeholder with no matching capture, got nil error")
+	}
+}
+
+func TestRuleCompileAcceptsKnownPlaceholders(t *testing.T) {
+	r := &Rule{
+		Match:    "/api/*/**",
+		Query:    map[string]string{"id": "*"},
+		EventKey: "${method}_${1}_${2}_${query.id}",
+	}
+	if err := r.Compile(); err != nil {
+		t.Fatalf("unexpected error compiling rule with valid placeholders: %v", err)
+	}
+}
+
+func TestRuleCompileRejectsDoubleStarNotLast(t *testing.T) {
+	r := &Rule{Match: "/api/**/widgets", EventKey: "x"}
+	if err := r.Compile(); err == nil {
+		t.Fatal("expected Compile to reject \"**\" that isn't the last path segment")
+	}
+}
+
+func TestRuleEvaluateAndRenderEventKey(t *testing.T) {
+	r := &Rule{
+		Match:    "/api/*/widgets/**",
+		EventKey: "api_${1}_${2}",
+	}
+	if err := r.Compile(); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	segments := SplitPathSegments("/api/v1/widgets/123/extra")
+	captures, ok := r.Evaluate("GET", segments, url.Values{})
+	if !ok {
+		t.Fatalf("expected rule to match segments %v", segments)
+	}
+	eventKey, err := r.RenderEventKey(captures)
+	if err != nil {
+		t.Fatalf("RenderEventKey failed: %v", err)
+	}
+	if want := "api_v1_123/extra"; eventKey != want {
+		t.Fatalf("RenderEventKey = %q, want %q", eventKey, want)
+	}
+}
+
+func TestRuleEvaluateQueryConstraint(t *testing.T) {
+	r := &Rule{
+		Match:    "/search",
+		Query:    map[string]string{"type": "widgets"},
+		EventKey: "search_${query.type}",
+	}
+	if err := r.Compile(); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	segments := SplitPathSegments("/search")
+	if _, ok := r.Evaluate("GET", segments, url.Values{"type": {"gadgets"}}); ok {
+		t.Fatal("expected rule not to match when query constraint differs")
+	}
+	captures, ok := r.Evaluate("GET", segments, url.Values{"type": {"widgets"}})
+	if !ok {
+		t.Fatal("expected rule to match when query constraint is satisfied")
+	}
+	eventKey, err := r.RenderEventKey(captures)
+	if err != nil {
+		t.Fatalf("RenderEventKey failed: %v", err)
+	}
+	if want := "search_widgets"; eventKey != want {
+		t.Fatalf("RenderEventKey = %q, want %q", eventKey, want)
+	}
+}
+
+func TestRuleEvaluateMethodMismatch(t *testing.T) {
+	r := &Rule{Match: "/api", Method: "POST", EventKey: "api"}
+	if err := r.Compile(); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if _, ok := r.Evaluate("GET", SplitPathSegments("/api"), url.Values{}); ok {
+		t.Fatal("expected rule restricted to POST not to match a GET request")
+	}
+}
+
+func TestRuleEvaluateSegmentCountMismatch(t *testing.T) {
+	r := &Rule{Match: "/api/*", EventKey: "api_${1}"}
+	if err := r.Compile(); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if _, ok := r.Evaluate("GET", SplitPathSegments("/api/v1/extra"), url.Values{}); ok {
+		t.Fatal("expected rule without a trailing \"**\" not to match a longer path")
+	}
+}
+
+func TestSplitPathSegments(t *testing.T) {
+	cases := map[string][]string{
+		"/api/v1/users/": {"api", "v1", "users"},
+		"/":              nil,
+		"":               nil,
+	}
+	for input, want := range cases {
+		got := SplitPathSegments(input)
+		if len(got) != len(want) {
+			t.Fatalf("SplitPathSegments(%q) = %v, want %v", input, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("SplitPathSegments(%q) = %v, want %v", input, got, want)
+			}
+		}
+	}
+}
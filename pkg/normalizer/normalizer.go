@@ -1,13 +1,17 @@
 package normalizer
 
 import (
+	"container/list"
 	"fmt"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 	"gitlab.seznam.net/sklik-devops/slo-exporter/pkg/event"
+	"gitlab.seznam.net/sklik-devops/slo-exporter/pkg/normalizer/mapping"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/asaskevich/govalidator"
@@ -15,21 +19,39 @@ import (
 )
 
 const (
-	eventKeyFieldSeparator = ":"
-	numberPlaceholder      = "0"
-	ipPlaceholder          = ":ip"
-	hashPlaceholder        = ":hash"
-	uuidPlaceholder        = ":uuid"
-	imagePlaceholder       = ":image"
-	fontPlaceholder        = ":font"
-	pathItemsSeparator     = "/"
-	component              = "normalizer"
+	eventKeyFieldSeparator  = ":"
+	numberPlaceholder       = "0"
+	ipPlaceholder           = ":ip"
+	hashPlaceholder         = ":hash"
+	uuidPlaceholder         = ":uuid"
+	imagePlaceholder        = ":image"
+	fontPlaceholder         = ":font"
+	jwtPlaceholder          = ":jwt"
+	base64Placeholder       = ":base64"
+	emailPlaceholder        = ":email"
+	localePlaceholder       = ":locale"
+	defaultOverflowEventKey = ":overflow"
+	pathItemsSeparator      = "/"
+	component               = "normalizer"
+
+	// sanitizerPositionLast restricts a sanitizer to only the last path segment,
+	// mirroring the historical SanitizeImages/SanitizeFonts behaviour.
+	sanitizerPositionLast = "last"
+	// defaultBase64MinLength is the default minimum length a path segment must have
+	// before the "base64" sanitizer considers it a token rather than a short word.
+	defaultBase64MinLength = 24
+	// defaultMaxEventKeysTTL is the MaxEventKeysTTL used when MaxEventKeys is set but
+	// MaxEventKeysTTL is left at its zero value.
+	defaultMaxEventKeysTTL = time.Hour
 )
 
 var (
-	log                 *logrus.Entry
-	imageExtensionRegex = regexp.MustCompile(`(?i)\.(?:png|jpg|jpeg|svg|tif|tiff|gif|ico)$`)
-	fontExtensionRegex  = regexp.MustCompile(`(?i)\.(?:ttf|woff)$`)
+	log                   *logrus.Entry
+	imageExtensionRegex   = regexp.MustCompile(`(?i)\.(?:png|jpg|jpeg|svg|tif|tiff|gif|ico)$`)
+	fontExtensionRegex    = regexp.MustCompile(`(?i)\.(?:ttf|woff)$`)
+	base64urlSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	base64TokenRegex      = regexp.MustCompile(`^[A-Za-z0-9+/_-]+={0,2}$`)
+	localeRegex           = regexp.MustCompile(`^[a-z]{2}[_-][A-Z]{2}$`)
 )
 
 func init() {
@@ -52,6 +74,204 @@ func (n *replacer) process(path string) string {
 	return path
 }
 
+// SanitizerConfig configures a single entry of the Sanitizers registry. Name selects a
+// built-in detector ("hash", "number", "uid", "ip", "image", "font", "jwt", "base64",
+// "email", "locale") or the generic "regex" detector. Placeholder overrides the built-in's
+// default replacement. SegmentPosition, when set to "last", restricts the sanitizer to the
+// last path segment only (as the built-in image/font detectors always do). Regexp and
+// MinLength are only used by the "regex" and "base64" detectors respectively.
+type SanitizerConfig struct {
+	Name            string
+	Placeholder     string
+	SegmentPosition string
+	Regexp          string
+	MinLength       int
+}
+
+// sanitizer is a compiled SanitizerConfig: a matcher plus the placeholder it replaces a
+// matching path segment with.
+type sanitizer struct {
+	SanitizerConfig
+	matchFn func(segment string) bool
+}
+
+// process replaces segment with the sanitizer's placeholder if it matches and the
+// segment's position satisfies SegmentPosition. It returns the (possibly unchanged)
+// segment and whether a replacement was made.
+func (s *sanitizer) process(segment string, isLastSegment bool) (string, bool) {
+	if s.SegmentPosition == sanitizerPositionLast && !isLastSegment {
+		return segment, false
+	}
+	if s.matchFn(segment) {
+		return s.Placeholder, true
+	}
+	return segment, false
+}
+
+func newSanitizer(cfg SanitizerConfig, defaultPlaceholder string, matchFn func(string) bool) *sanitizer {
+	if cfg.Placeholder == "" {
+		cfg.Placeholder = defaultPlaceholder
+	}
+	return &sanitizer{SanitizerConfig: cfg, matchFn: matchFn}
+}
+
+func isJWT(segment string) bool {
+	parts := strings.Split(segment, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" || !base64urlSegmentRegex.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizerRegistry maps a SanitizerConfig.Name to a constructor producing the compiled
+// sanitizer. New detectors are added here.
+var sanitizerRegistry = map[string]func(cfg SanitizerConfig) (*sanitizer, error){
+	"hash": func(cfg SanitizerConfig) (*sanitizer, error) {
+		return newSanitizer(cfg, hashPlaceholder, func(s string) bool {
+			return govalidator.IsMD5(s) || govalidator.IsSHA1(s) || govalidator.IsSHA256(s)
+		}), nil
+	},
+	"number": func(cfg SanitizerConfig) (*sanitizer, error) {
+		return newSanitizer(cfg, numberPlaceholder, func(s string) bool {
+			return govalidator.IsNumeric(s) || govalidator.IsHexadecimal(s)
+		}), nil
+	},
+	"uid": func(cfg SanitizerConfig) (*sanitizer, error) {
+		return newSanitizer(cfg, uuidPlaceholder, func(s string) bool {
+			return govalidator.IsUUID(s) || govalidator.IsUUIDv4(s)
+		}), nil
+	},
+	"ip": func(cfg SanitizerConfig) (*sanitizer, error) {
+		return newSanitizer(cfg, ipPlaceholder, govalidator.IsIP), nil
+	},
+	"image": func(cfg SanitizerConfig) (*sanitizer, error) {
+		s := newSanitizer(cfg, imagePlaceholder, imageExtensionRegex.MatchString)
+		s.SegmentPosition = sanitizerPositionLast
+		return s, nil
+	},
+	"font": func(cfg SanitizerConfig) (*sanitizer, error) {
+		s := newSanitizer(cfg, fontPlaceholder, fontExtensionRegex.MatchString)
+		s.SegmentPosition = sanitizerPositionLast
+		return s, nil
+	},
+	"jwt": func(cfg SanitizerConfig) (*sanitizer, error) {
+		return newSanitizer(cfg, jwtPlaceholder, isJWT), nil
+	},
+	"base64": func(cfg SanitizerConfig) (*sanitizer, error) {
+		minLength := cfg.MinLength
+		if minLength <= 0 {
+			minLength = defaultBase64MinLength
+		}
+		return newSanitizer(cfg, base64Placeholder, func(s string) bool {
+			return len(s) >= minLength && base64TokenRegex.MatchString(s)
+		}), nil
+	},
+	"email": func(cfg SanitizerConfig) (*sanitizer, error) {
+		return newSanitizer(cfg, emailPlaceholder, govalidator.IsEmail), nil
+	},
+	"locale": func(cfg SanitizerConfig) (*sanitizer, error) {
+		return newSanitizer(cfg, localePlaceholder, localeRegex.MatchString), nil
+	},
+	"regex": func(cfg SanitizerConfig) (*sanitizer, error) {
+		if cfg.Regexp == "" {
+			return nil, fmt.Errorf("sanitizer %q: Regexp must be set", cfg.Name)
+		}
+		if cfg.Placeholder == "" {
+			return nil, fmt.Errorf("sanitizer %q: Placeholder must be set", cfg.Name)
+		}
+		compiled, err := regexp.Compile(cfg.Regexp)
+		if err != nil {
+			return nil, fmt.Errorf("sanitizer %q: failed to compile Regexp %s: %w", cfg.Name, cfg.Regexp, err)
+		}
+		return newSanitizer(cfg, cfg.Placeholder, compiled.MatchString), nil
+	},
+}
+
+// legacySanitizerConfigs translates the deprecated SanitizeHashes/Numbers/Uids/Ips/
+// Images/Fonts booleans into their equivalent Sanitizers registry entries, preserving
+// their historical evaluation order.
+func legacySanitizerConfigs(config *requestNormalizerConfig) []SanitizerConfig {
+	var configs []SanitizerConfig
+	if config.SanitizeHashes {
+		configs = append(configs, SanitizerConfig{Name: "hash"})
+	}
+	if config.SanitizeNumbers {
+		configs = append(configs, SanitizerConfig{Name: "number"})
+	}
+	if config.SanitizeUids {
+		configs = append(configs, SanitizerConfig{Name: "uid"})
+	}
+	if config.SanitizeIps {
+		configs = append(configs, SanitizerConfig{Name: "ip"})
+	}
+	if config.SanitizeImages {
+		configs = append(configs, SanitizerConfig{Name: "image"})
+	}
+	if config.SanitizeFonts {
+		configs = append(configs, SanitizerConfig{Name: "font"})
+	}
+	return configs
+}
+
+// normalizerCache is a fixed-size, concurrency-safe LRU cache mapping a request's cache
+// key to its already computed EventKey, so that repeated URLs skip the regex/govalidator
+// work in getNormalizedEventKey.
+type normalizerCache struct {
+	mu       sync.Mutex
+	capacity int
+	list     *list.List
+	items    map[string]*list.Element
+}
+
+type normalizerCacheEntry struct {
+	key   string
+	value string
+}
+
+func newNormalizerCache(capacity int) *normalizerCache {
+	return &normalizerCache{
+		capacity: capacity,
+		list:     list.New(),
+		items:    make(map[string]*list.Element, capacity),
+	}
+}
+
+func (c *normalizerCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	c.list.MoveToFront(element)
+	return element.Value.(*normalizerCacheEntry).value, true
+}
+
+func (c *normalizerCache) add(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.list.MoveToFront(element)
+		element.Value.(*normalizerCacheEntry).value = value
+		return
+	}
+	c.items[key] = c.list.PushFront(&normalizerCacheEntry{key: key, value: value})
+	if c.list.Len() > c.capacity {
+		oldest := c.list.Back()
+		if oldest != nil {
+			c.list.Remove(oldest)
+			delete(c.items, oldest.Value.(*normalizerCacheEntry).key)
+		}
+	}
+}
+
 func NewFromViper(viperConfig *viper.Viper) (*requestNormalizer, error) {
 	config := &requestNormalizerConfig{}
 	if err := viperConfig.UnmarshalExact(config); err != nil {
@@ -62,43 +282,182 @@ func NewFromViper(viperConfig *viper.Viper) (*requestNormalizer, error) {
 
 type requestNormalizerConfig struct {
 	GetParamWithEventIdentifier string
-	ReplaceRules                []replacer
-	SanitizeHashes              bool
-	SanitizeNumbers             bool
-	SanitizeUids                bool
-	SanitizeIps                 bool
-	SanitizeImages              bool
-	SanitizeFonts               bool
+	// MappingRules are tried in order before falling back to ReplaceRules/Sanitizers based
+	// normalization. The resulting EventKey is always prefixed with "<Method>:", same as the
+	// fallback path, so a rule's EventKey template only needs to describe the path/query
+	// portion of the key.
+	MappingRules []mapping.Rule
+	ReplaceRules []replacer
+	// Sanitizers is the pluggable sanitizer registry. Entries run, in order, over every
+	// non-empty path segment that hasn't already been replaced, mirroring and extending the
+	// deprecated boolean fields below.
+	Sanitizers []SanitizerConfig
+	// Deprecated: use Sanitizers with Name "hash"/"number"/"uid"/"ip"/"image"/"font"
+	// instead. These are translated into equivalent Sanitizers entries at config load and
+	// run before any explicit Sanitizers entries.
+	SanitizeHashes  bool
+	SanitizeNumbers bool
+	SanitizeUids    bool
+	SanitizeIps     bool
+	SanitizeImages  bool
+	SanitizeFonts   bool
+	// MaxEventKeys limits the number of distinct EventKeys the normalizer will produce.
+	// Zero (the default) means unlimited. Once the limit is reached, any new EventKey is
+	// collapsed into OverflowEventKey instead.
+	MaxEventKeys int
+	// OverflowEventKey is the EventKey used for events collapsed by the MaxEventKeys guard.
+	// Defaults to ":overflow".
+	OverflowEventKey string
+	// MaxEventKeysTTL bounds how long an EventKey counts against MaxEventKeys after it was
+	// last seen; once it goes unseen for longer than this, it is evicted and the slot is
+	// freed for a new distinct EventKey. This makes the guard a sliding window rather than
+	// a permanent latch: a cardinality spike drains back down once it subsides. Defaults to
+	// one hour when MaxEventKeys is set and this is left at zero.
+	MaxEventKeysTTL time.Duration
+	// Workers is the number of goroutines processing inputEventsChan concurrently in Run.
+	// Defaults to 1, preserving the original serial behaviour.
+	Workers int
+	// CacheSize bounds an LRU cache of already normalized EventKeys, keyed by method, raw
+	// path and the query values relevant to normalization. Zero (the default) disables the
+	// cache.
+	CacheSize int
 }
 
 // New returns requestNormalizer which allows to add Key to RequestEvent
 func NewFromConfig(config *requestNormalizerConfig) (*requestNormalizer, error) {
+	overflowEventKey := config.OverflowEventKey
+	if overflowEventKey == "" {
+		overflowEventKey = defaultOverflowEventKey
+	}
+	maxEventKeysTTL := config.MaxEventKeysTTL
+	if maxEventKeysTTL <= 0 {
+		maxEventKeysTTL = defaultMaxEventKeysTTL
+	}
+	workers := config.Workers
+	if workers < 1 {
+		workers = 1
+	}
 	normalizer := requestNormalizer{
 		getParamWithEventIdentifier: config.GetParamWithEventIdentifier,
+		mappingRules:                config.MappingRules,
 		replaceRules:                config.ReplaceRules,
-		sanitizeHashes:              config.SanitizeHashes,
-		sanitizeNumbers:             config.SanitizeNumbers,
-		sanitizeUids:                config.SanitizeUids,
-		sanitizeIps:                 config.SanitizeIps,
-		sanitizeImages:              config.SanitizeImages,
-		sanitizeFonts:               config.SanitizeFonts,
+		sanitizerConfigs:            append(legacySanitizerConfigs(config), config.Sanitizers...),
+		maxEventKeys:                config.MaxEventKeys,
+		maxEventKeysTTL:             maxEventKeysTTL,
+		overflowEventKey:            overflowEventKey,
+		seenEventKeys:               make(map[string]time.Time),
+		workers:                     workers,
+	}
+	if config.CacheSize > 0 {
+		normalizer.cache = newNormalizerCache(config.CacheSize)
 	}
 	if err := normalizer.precompileRegexps(); err != nil {
 		return nil, err
 	}
+	if err := normalizer.compileMappingRules(); err != nil {
+		return nil, err
+	}
+	if err := normalizer.compileSanitizers(); err != nil {
+		return nil, err
+	}
 	return &normalizer, nil
 }
 
 type requestNormalizer struct {
 	getParamWithEventIdentifier string
+	mappingRules                []mapping.Rule
 	replaceRules                []replacer
-	sanitizeHashes              bool
-	sanitizeNumbers             bool
-	sanitizeUids                bool
-	sanitizeIps                 bool
-	sanitizeImages              bool
-	sanitizeFonts               bool
+	sanitizerConfigs            []SanitizerConfig
+	sanitizers                  []*sanitizer
 	observer                    prometheus.Observer
+
+	maxEventKeys           int
+	maxEventKeysTTL        time.Duration
+	overflowEventKey       string
+	seenEventKeysMu        sync.Mutex
+	seenEventKeys          map[string]time.Time
+	overflowEventsCounter  *prometheus.CounterVec
+	distinctEventKeysGauge prometheus.Gauge
+
+	workers           int
+	relevantQueryKeys []string
+	cache             *normalizerCache
+	cacheHitCounter   prometheus.Counter
+	cacheMissCounter  prometheus.Counter
+}
+
+// SetCacheHitCounter sets the counter incremented on every EventKey cache hit.
+func (rn *requestNormalizer) SetCacheHitCounter(counter prometheus.Counter) {
+	rn.cacheHitCounter = counter
+}
+
+// SetCacheMissCounter sets the counter incremented on every EventKey cache miss.
+func (rn *requestNormalizer) SetCacheMissCounter(counter prometheus.Counter) {
+	rn.cacheMissCounter = counter
+}
+
+// SetOverflowEventsCounter sets the "reason" labelled counter incremented whenever the
+// MaxEventKeys guard collapses an EventKey into the overflow bucket. Unset by default, so
+// that multiple requestNormalizer instances in the same process don't stomp on a single
+// shared metric; callers that want the metric exported must register it themselves (e.g.
+// with prometheus.MustRegister) and pass it in here, the same way SetPrometheusObserver
+// works.
+func (rn *requestNormalizer) SetOverflowEventsCounter(counter *prometheus.CounterVec) {
+	rn.overflowEventsCounter = counter
+}
+
+// SetDistinctEventKeysGauge sets the gauge tracking the current number of distinct
+// EventKeys tracked by the MaxEventKeys guard. See SetOverflowEventsCounter.
+func (rn *requestNormalizer) SetDistinctEventKeysGauge(gauge prometheus.Gauge) {
+	rn.distinctEventKeysGauge = gauge
+}
+
+// limitCardinality enforces MaxEventKeys: once the distinct EventKey count reaches the
+// configured limit, any newly seen EventKey is collapsed into overflowEventKey so that a
+// single misbehaving caller cannot cause unbounded SLO metric cardinality. Tracking is a
+// sliding window, not a permanent latch: an EventKey not seen again within MaxEventKeysTTL
+// is evicted and its slot freed, so a transient cardinality spike drains back down once it
+// subsides instead of permanently starving later legitimate keys.
+func (rn *requestNormalizer) limitCardinality(eventKey string) string {
+	if rn.maxEventKeys <= 0 {
+		return eventKey
+	}
+	now := time.Now()
+	rn.seenEventKeysMu.Lock()
+	defer rn.seenEventKeysMu.Unlock()
+
+	rn.evictExpiredEventKeysLocked(now)
+
+	if _, ok := rn.seenEventKeys[eventKey]; ok {
+		rn.seenEventKeys[eventKey] = now
+		return eventKey
+	}
+	if len(rn.seenEventKeys) >= rn.maxEventKeys {
+		if rn.overflowEventsCounter != nil {
+			rn.overflowEventsCounter.WithLabelValues("cardinality").Inc()
+		}
+		return rn.overflowEventKey
+	}
+	rn.seenEventKeys[eventKey] = now
+	if rn.distinctEventKeysGauge != nil {
+		rn.distinctEventKeysGauge.Set(float64(len(rn.seenEventKeys)))
+	}
+	return eventKey
+}
+
+// evictExpiredEventKeysLocked removes every tracked EventKey not seen within
+// maxEventKeysTTL. Callers must hold seenEventKeysMu.
+func (rn *requestNormalizer) evictExpiredEventKeysLocked(now time.Time) {
+	evicted := false
+	for key, lastSeen := range rn.seenEventKeys {
+		if now.Sub(lastSeen) > rn.maxEventKeysTTL {
+			delete(rn.seenEventKeys, key)
+			evicted = true
+		}
+	}
+	if evicted && rn.distinctEventKeysGauge != nil {
+		rn.distinctEventKeysGauge.Set(float64(len(rn.seenEventKeys)))
+	}
 }
 
 func (rn *requestNormalizer) SetPrometheusObserver(observer prometheus.Observer) {
@@ -122,6 +481,104 @@ func (rn *requestNormalizer) precompileRegexps() error {
 	return nil
 }
 
+// compileMappingRules precompiles the glob pattern and EventKey template of every
+// configured mapping rule, failing fast on invalid config. It also collects the set of
+// query parameter names referenced by rules' Query constraints, since those values are
+// relevant to the EventKey cache key.
+func (rn *requestNormalizer) compileMappingRules() error {
+	relevantQueryKeys := make(map[string]struct{})
+	for i := range rn.mappingRules {
+		if err := rn.mappingRules[i].Compile(); err != nil {
+			return fmt.Errorf("invalid MappingRules[%d]: %w", i, err)
+		}
+		for param := range rn.mappingRules[i].Query {
+			relevantQueryKeys[param] = struct{}{}
+		}
+	}
+	rn.relevantQueryKeys = rn.relevantQueryKeys[:0]
+	for param := range relevantQueryKeys {
+		rn.relevantQueryKeys = append(rn.relevantQueryKeys, param)
+	}
+	sort.Strings(rn.relevantQueryKeys)
+	return nil
+}
+
+// matchMappingRule tries each configured mapping rule in declaration order and returns the
+// EventKey of the first match, prefixed with the request method just like the fallback
+// normalizePath path so that the two EventKey schemas stay consistent.
+func (rn *requestNormalizer) matchMappingRule(event *event.HttpRequest) (string, bool) {
+	pathSegments := mapping.SplitPathSegments(event.URL.Path)
+	for _, rule := range rn.mappingRules {
+		captures, ok := rule.Evaluate(event.Method, pathSegments, event.URL.Query())
+		if !ok {
+			continue
+		}
+		eventKey, err := rule.RenderEventKey(rn.sanitizeCaptures(captures))
+		if err != nil {
+			log.Warnf("mapping rule %q matched but failed to render EventKey: %s", rule.Match, err)
+			continue
+		}
+		return event.Method + eventKeyFieldSeparator + eventKey, true
+	}
+	return "", false
+}
+
+// sanitizeCaptures runs the configured sanitizers over every "*"/"**" path capture and
+// "query.*" capture before it is templated into a mapping rule's EventKey, so that a capture
+// gets the same cardinality/PII protection as the fallback normalizePath path instead of
+// leaking raw, unbounded request data straight into a Prometheus label. The synthetic
+// "method" capture is passed through unchanged.
+func (rn *requestNormalizer) sanitizeCaptures(captures map[string]string) map[string]string {
+	sanitized := make(map[string]string, len(captures))
+	for key, value := range captures {
+		if key == "method" {
+			sanitized[key] = value
+			continue
+		}
+		sanitized[key] = rn.sanitizeCaptureValue(value)
+	}
+	return sanitized
+}
+
+// sanitizeCaptureValue applies the configured sanitizers to value the same way normalizePath
+// applies them to a request path: value is split on "/" (a "**" capture may itself contain
+// several segments; anything else is a single segment) and each non-empty segment is passed
+// through the sanitizer chain independently.
+func (rn *requestNormalizer) sanitizeCaptureValue(value string) string {
+	segments := strings.Split(value, pathItemsSeparator)
+	itemsCount := len(segments)
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		isLastSegment := i+1 == itemsCount
+		for _, s := range rn.sanitizers {
+			if replaced, matched := s.process(segment, isLastSegment); matched {
+				segments[i] = replaced
+				break
+			}
+		}
+	}
+	return strings.Join(segments, pathItemsSeparator)
+}
+
+// compileSanitizers resolves every configured SanitizerConfig against sanitizerRegistry,
+// failing fast on an unknown Name or invalid detector config.
+func (rn *requestNormalizer) compileSanitizers() error {
+	for i, cfg := range rn.sanitizerConfigs {
+		build, ok := sanitizerRegistry[cfg.Name]
+		if !ok {
+			return fmt.Errorf("invalid Sanitizers[%d]: unknown sanitizer %q", i, cfg.Name)
+		}
+		compiled, err := build(cfg)
+		if err != nil {
+			return fmt.Errorf("invalid Sanitizers[%d]: %w", i, err)
+		}
+		rn.sanitizers = append(rn.sanitizers, compiled)
+	}
+	return nil
+}
+
 func (rn *requestNormalizer) normalizePath(rawPath string) string {
 	if rawPath == "" {
 		return "/"
@@ -135,43 +592,74 @@ func (rn *requestNormalizer) normalizePath(rawPath string) string {
 		if item == "" {
 			continue
 		}
-
-		if rn.sanitizeHashes && (govalidator.IsMD5(item) || govalidator.IsSHA1(item) || govalidator.IsSHA256(item)) {
-			pathItems[i] = hashPlaceholder
-			continue
-		}
-		if rn.sanitizeNumbers && (govalidator.IsNumeric(item) || govalidator.IsHexadecimal(item)) {
-			pathItems[i] = numberPlaceholder
-			continue
+		isLastSegment := i+1 == itemsCount
+		for _, s := range rn.sanitizers {
+			if replaced, matched := s.process(item, isLastSegment); matched {
+				pathItems[i] = replaced
+				break
+			}
 		}
+	}
+	return strings.Join(pathItems, pathItemsSeparator)
+}
 
-		if rn.sanitizeUids && (govalidator.IsUUID(item) || govalidator.IsUUIDv4(item)) {
-			pathItems[i] = uuidPlaceholder
-			continue
+// cacheKeyFor builds the EventKey cache key for event: the method, the raw path and every
+// query value that can influence the resulting EventKey (the configured
+// GetParamWithEventIdentifier and any parameter referenced by a MappingRules Query
+// constraint).
+func (rn *requestNormalizer) cacheKeyFor(event *event.HttpRequest) string {
+	var key strings.Builder
+	key.WriteString(event.Method)
+	key.WriteByte('\x00')
+	key.WriteString(event.URL.Path)
+	if rn.getParamWithEventIdentifier != "" {
+		for _, value := range event.URL.Query()[rn.getParamWithEventIdentifier] {
+			key.WriteByte('\x00')
+			key.WriteString(value)
 		}
+	}
+	for _, param := range rn.relevantQueryKeys {
+		key.WriteByte('\x00')
+		key.WriteString(event.URL.Query().Get(param))
+	}
+	return key.String()
+}
 
-		if rn.sanitizeIps && govalidator.IsIP(item) {
-			pathItems[i] = ipPlaceholder
-			continue
-		}
+// getNormalizedEventKey computes event's EventKey, consulting/populating the EventKey cache
+// (if configured) with the *pre*-cardinality-guard key. limitCardinality is applied after
+// every cache lookup, on both hit and miss, so a cached EventKey's cardinality bucketing is
+// re-evaluated on every request rather than being frozen at whatever limitCardinality
+// returned the first time it was computed — otherwise an EventKey that overflowed once would
+// stay collapsed into the overflow bucket forever, even after MaxEventKeysTTL has freed its
+// slot.
+func (rn *requestNormalizer) getNormalizedEventKey(event *event.HttpRequest) string {
+	if rn.cache == nil {
+		return rn.limitCardinality(rn.computeRawEventKey(event))
+	}
 
-		// replace all numbers with zero in the last part of the rawPath
-		if i+1 == itemsCount {
-			if rn.sanitizeImages && imageExtensionRegex.MatchString(item) {
-				pathItems[i] = imagePlaceholder
-				continue
-			}
-			if rn.sanitizeFonts && fontExtensionRegex.MatchString(item) {
-				pathItems[i] = fontPlaceholder
-				continue
-			}
-			continue
+	cacheKey := rn.cacheKeyFor(event)
+	if rawEventKey, ok := rn.cache.get(cacheKey); ok {
+		if rn.cacheHitCounter != nil {
+			rn.cacheHitCounter.Inc()
 		}
+		return rn.limitCardinality(rawEventKey)
 	}
-	return strings.Join(pathItems, pathItemsSeparator)
+	if rn.cacheMissCounter != nil {
+		rn.cacheMissCounter.Inc()
+	}
+	rawEventKey := rn.computeRawEventKey(event)
+	rn.cache.add(cacheKey, rawEventKey)
+	return rn.limitCardinality(rawEventKey)
 }
 
-func (rn *requestNormalizer) getNormalizedEventKey(event *event.HttpRequest) string {
+// computeRawEventKey computes event's EventKey before the MaxEventKeys cardinality guard is
+// applied. See getNormalizedEventKey for why this, and not the post-guard key, is what gets
+// cached.
+func (rn *requestNormalizer) computeRawEventKey(event *event.HttpRequest) string {
+	if eventKey, ok := rn.matchMappingRule(event); ok {
+		return eventKey
+	}
+
 	var eventIdentifiers = []string{event.Method}
 	eventIdentifiers = append(eventIdentifiers, rn.normalizePath(event.URL.Path))
 	if rn.getParamWithEventIdentifier != "" {
@@ -186,21 +674,44 @@ func (rn *requestNormalizer) getNormalizedEventKey(event *event.HttpRequest) str
 	return strings.Join(eventIdentifiers, eventKeyFieldSeparator)
 }
 
-// Run event replacer receiving events and filling their Key if not already filled.
+// processEvent fills in newEvent's Key if not already set, observing the processing
+// duration.
+func (rn *requestNormalizer) processEvent(newEvent *event.HttpRequest) {
+	start := time.Now()
+	if newEvent.EventKey != "" {
+		log.Debugf("skipping newEvent normalization, already has Key: %s", newEvent.EventKey)
+	} else {
+		newEvent.EventKey = rn.getNormalizedEventKey(newEvent)
+		log.Debugf("processed newEvent with Key: %s", newEvent.EventKey)
+	}
+	rn.observeDuration(start)
+}
+
+// Run event replacer receiving events and filling their Key if not already filled. Events
+// are fanned out across Workers goroutines (1 by default, preserving the original serial
+// behaviour); the output channel is closed once the input channel is closed and every
+// worker has finished draining it.
 func (rn *requestNormalizer) Run(inputEventsChan <-chan *event.HttpRequest, outputEventsChan chan<- *event.HttpRequest) {
-	go func() {
-		defer close(outputEventsChan)
-		for newEvent := range inputEventsChan {
-			start := time.Now()
-			if newEvent.EventKey != "" {
-				log.Debugf("skipping newEvent normalization, already has Key: %s", newEvent.EventKey)
-			} else {
-				newEvent.EventKey = rn.getNormalizedEventKey(newEvent)
-				log.Debugf("processed newEvent with Key: %s", newEvent.EventKey)
+	workers := rn.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for newEvent := range inputEventsChan {
+				rn.processEvent(newEvent)
+				outputEventsChan <- newEvent
 			}
-			outputEventsChan <- newEvent
-			rn.observeDuration(start)
-		}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(outputEventsChan)
 		log.Info("input channel closed, finishing")
 	}()
 }
@@ -0,0 +1,100 @@
+package normalizer
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"gitlab.seznam.net/sklik-devops/slo-exporter/pkg/event"
+)
+
+func benchmarkRequests(n int) []*event.HttpRequest {
+	requests := make([]*event.HttpRequest, n)
+	for i := range requests {
+		parsed, err := url.Parse(fmt.Sprintf("/api/users/%d/orders/%d", i%50, i))
+		if err != nil {
+			panic(err)
+		}
+		requests[i] = &event.HttpRequest{Method: "GET", URL: parsed}
+	}
+	return requests
+}
+
+// BenchmarkGetNormalizedEventKeyWithoutCache measures the baseline cost of normalizing every
+// request from scratch, i.e. CacheSize left at its default of zero.
+func BenchmarkGetNormalizedEventKeyWithoutCache(b *testing.B) {
+	rn, err := NewFromConfig(&requestNormalizerConfig{Sanitizers: []SanitizerConfig{{Name: "number"}}})
+	if err != nil {
+		b.Fatalf("NewFromConfig failed: %v", err)
+	}
+	requests := benchmarkRequests(50)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rn.getNormalizedEventKey(requests[i%len(requests)])
+	}
+}
+
+// BenchmarkGetNormalizedEventKeyWithCache measures the same workload with CacheSize set large
+// enough to hold every distinct request seen, demonstrating the improvement the LRU cache is
+// meant to provide for repeated URLs.
+func BenchmarkGetNormalizedEventKeyWithCache(b *testing.B) {
+	rn, err := NewFromConfig(&requestNormalizerConfig{
+		Sanitizers: []SanitizerConfig{{Name: "number"}},
+		CacheSize:  128,
+	})
+	if err != nil {
+		b.Fatalf("NewFromConfig failed: %v", err)
+	}
+	requests := benchmarkRequests(50)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rn.getNormalizedEventKey(requests[i%len(requests)])
+	}
+}
+
+// benchmarkRun drives rn.Run with concurrency workers over 1000 requests, draining the output
+// channel as it goes.
+func benchmarkRun(b *testing.B, workers int) {
+	rn, err := NewFromConfig(&requestNormalizerConfig{
+		Sanitizers: []SanitizerConfig{{Name: "number"}},
+		Workers:    workers,
+	})
+	if err != nil {
+		b.Fatalf("NewFromConfig failed: %v", err)
+	}
+	requests := benchmarkRequests(1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		in := make(chan *event.HttpRequest)
+		out := make(chan *event.HttpRequest)
+		rn.Run(in, out)
+
+		done := make(chan struct{})
+		go func() {
+			for range out {
+			}
+			close(done)
+		}()
+
+		for _, req := range requests {
+			in <- &event.HttpRequest{Method: req.Method, URL: req.URL}
+		}
+		close(in)
+		<-done
+	}
+}
+
+// BenchmarkRunSingleWorker measures Run's original serial behaviour (Workers left at its
+// default of 1).
+func BenchmarkRunSingleWorker(b *testing.B) {
+	benchmarkRun(b, 1)
+}
+
+// BenchmarkRunParallelWorkers measures Run fanned out across multiple workers, demonstrating
+// the throughput improvement Workers is meant to provide.
+func BenchmarkRunParallelWorkers(b *testing.B) {
+	benchmarkRun(b, 8)
+}
@@ -0,0 +1,80 @@
+package normalizer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"gitlab.seznam.net/sklik-devops/slo-exporter/pkg/event"
+)
+
+// TestRunWithMultipleWorkersProcessesAllEvents checks that Run, fanned out across several
+// Workers, emits every input event exactly once (by identity, not by position) and closes
+// the output channel once the input is drained. Downstream consumers must not assume Run
+// preserves input ordering when Workers > 1.
+func TestRunWithMultipleWorkersProcessesAllEvents(t *testing.T) {
+	rn := mustNormalizer(t, &requestNormalizerConfig{Workers: 4})
+
+	const eventCount = 200
+	sent := make([]*event.HttpRequest, eventCount)
+	for i := range sent {
+		req := mustRequest(t, "GET", fmt.Sprintf("/api/items/%d", i))
+		req.EventKey = fmt.Sprintf("marker-%d", i)
+		sent[i] = req
+	}
+
+	in := make(chan *event.HttpRequest)
+	out := make(chan *event.HttpRequest)
+	rn.Run(in, out)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, req := range sent {
+			in <- req
+		}
+		close(in)
+	}()
+
+	seen := make(map[string]int, eventCount)
+	for req := range out {
+		seen[req.EventKey]++
+	}
+	wg.Wait()
+
+	if len(seen) != eventCount {
+		t.Fatalf("got %d distinct events out, want %d", len(seen), eventCount)
+	}
+	for marker, count := range seen {
+		if count != 1 {
+			t.Fatalf("event %q was emitted %d times, want exactly once", marker, count)
+		}
+	}
+}
+
+func TestCacheHitAndMissCountersIncrement(t *testing.T) {
+	rn := mustNormalizer(t, &requestNormalizerConfig{CacheSize: 10})
+	hitCounter := newTestCounter("test_cache_hits_total")
+	missCounter := newTestCounter("test_cache_misses_total")
+	rn.SetCacheHitCounter(hitCounter)
+	rn.SetCacheMissCounter(missCounter)
+
+	req := mustRequest(t, "GET", "/api/widgets/123")
+
+	rn.getNormalizedEventKey(req)
+	if got := testSimpleCounterValue(missCounter); got != 1 {
+		t.Fatalf("miss counter = %v, want 1 after the first, uncached lookup", got)
+	}
+	if got := testSimpleCounterValue(hitCounter); got != 0 {
+		t.Fatalf("hit counter = %v, want 0 after the first, uncached lookup", got)
+	}
+
+	rn.getNormalizedEventKey(req)
+	if got := testSimpleCounterValue(hitCounter); got != 1 {
+		t.Fatalf("hit counter = %v, want 1 after a repeated lookup of the same request", got)
+	}
+	if got := testSimpleCounterValue(missCounter); got != 1 {
+		t.Fatalf("miss counter = %v, want unchanged at 1 after a cache hit", got)
+	}
+}